@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// AuditEvent is one line written to the audit log for every resource this
+// tool deletes (or would delete, under DRY_RUN).
+type AuditEvent struct {
+	Timestamp    time.Time         `json:"ts"`
+	Action       string            `json:"action"`
+	ResourceType string            `json:"resource_type"`
+	ResourceID   string            `json:"resource_id"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	SizeBytes    int64             `json:"size_bytes"`
+	CreatedAt    time.Time         `json:"created_at,omitempty"`
+	DryRun       bool              `json:"dry_run"`
+}
+
+var (
+	auditWriter *lumberjack.Logger
+	auditMu     sync.Mutex
+)
+
+// InitAuditLog opens the audit log at the path given by AUDIT_LOG, rotated
+// via lumberjack using the same LOG_MAX_* env vars as the main log file.
+// It is a no-op if AUDIT_LOG is unset.
+func InitAuditLog() {
+	path := os.Getenv("AUDIT_LOG")
+	if path == "" {
+		return
+	}
+	auditWriter = &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    envIntOrDefault("LOG_MAX_SIZE_MB", 100),
+		MaxBackups: envIntOrDefault("LOG_MAX_BACKUPS", 3),
+		MaxAge:     envIntOrDefault("LOG_MAX_AGE_DAYS", 28),
+		Compress:   strings.EqualFold(os.Getenv("LOG_COMPRESS"), "true"),
+	}
+}
+
+// Audit appends one JSON line recording a resource deletion to the audit
+// log. It is a no-op if InitAuditLog was never called or AUDIT_LOG is unset.
+func Audit(event AuditEvent) {
+	if auditWriter == nil {
+		return
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		Errorf("Failed to marshal audit event: %v", err)
+		return
+	}
+	line = append(line, '\n')
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if _, err := auditWriter.Write(line); err != nil {
+		Errorf("Failed to write audit event: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ssgreg/journald"
+	"go.uber.org/zap/zapcore"
+)
+
+// journaldCore is a zapcore.Core that forwards log entries to the systemd
+// journal via github.com/ssgreg/journald, mapping zap levels to syslog
+// priorities and promoting structured fields to journald variables
+// (uppercased keys, AWS_CLEANER_ prefix).
+type journaldCore struct {
+	level zapcore.LevelEnabler
+	vars  map[string]string
+}
+
+func newJournaldCore(level zapcore.LevelEnabler) zapcore.Core {
+	return &journaldCore{level: level, vars: map[string]string{}}
+}
+
+func (c *journaldCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl)
+}
+
+func (c *journaldCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make(map[string]string, len(c.vars)+len(fields))
+	for k, v := range c.vars {
+		merged[k] = v
+	}
+	for k, v := range fieldsToVars(fields) {
+		merged[k] = v
+	}
+	return &journaldCore{level: c.level, vars: merged}
+}
+
+func (c *journaldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *journaldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	vars := make(map[string]string, len(c.vars)+len(fields)+1)
+	for k, v := range c.vars {
+		vars[k] = v
+	}
+	for k, v := range fieldsToVars(fields) {
+		vars[k] = v
+	}
+	if ent.Caller.Defined {
+		vars["AWS_CLEANER_CALLER"] = ent.Caller.String()
+	}
+
+	return journald.Send(ent.Message, levelToPriority(ent.Level), vars)
+}
+
+func (c *journaldCore) Sync() error {
+	return nil
+}
+
+// fieldsToVars flattens zap fields into journald variable names: uppercased
+// and prefixed with AWS_CLEANER_.
+func fieldsToVars(fields []zapcore.Field) map[string]string {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	vars := make(map[string]string, len(enc.Fields))
+	for k, v := range enc.Fields {
+		vars["AWS_CLEANER_"+strings.ToUpper(k)] = fmt.Sprintf("%v", v)
+	}
+	return vars
+}
+
+func levelToPriority(level zapcore.Level) journald.Priority {
+	switch level {
+	case zapcore.DebugLevel:
+		return journald.PriorityDebug
+	case zapcore.InfoLevel:
+		return journald.PriorityInfo
+	case zapcore.WarnLevel:
+		return journald.PriorityWarning
+	case zapcore.ErrorLevel:
+		return journald.PriorityErr
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return journald.PriorityCrit
+	case zapcore.FatalLevel:
+		return journald.PriorityEmerg
+	default:
+		return journald.PriorityInfo
+	}
+}
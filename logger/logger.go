@@ -5,13 +5,12 @@ Maintainers: Nim
 package logger
 
 import (
-	"compress/gzip"
 	"fmt"
-	"io"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
-	"sync"
-	"time"
+	"syscall"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -22,12 +21,20 @@ import (
 var Log *zap.Logger
 var SugaredLog *zap.SugaredLogger
 
-// Mutex for file lock to prevent concurrent file access
-var fileLock sync.Mutex
+// atomicLevel backs every destination's core so SetLevel can flip them all
+// at once, e.g. from the SIGUSR1 handler below.
+var atomicLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
 
-// InitLogger initializes the logger with file rotation and timestamped logs.
+// SetLevel updates the level of every active log destination.
+func SetLevel(level zapcore.Level) {
+	atomicLevel.SetLevel(level)
+}
+
+// InitLogger initializes the logger against the destinations named by
+// LOG_DESTINATION (comma-separated: "stdout", "file", "journald"; default
+// "stdout"), following the selector pattern used by frostfs-s3-lifecycler's
+// pickLogger.
 func InitLogger() {
-	// level
 	level := zap.InfoLevel
 	switch strings.ToUpper(os.Getenv("LOG_LEVEL")) {
 	case "DEBUG":
@@ -37,130 +44,108 @@ func InitLogger() {
 	case "ERROR":
 		level = zap.ErrorLevel
 	}
+	atomicLevel.SetLevel(level)
 
 	encCfg := zap.NewProductionEncoderConfig()
 	encCfg.TimeKey = "ts"
 	encCfg.EncodeTime = zapcore.ISO8601TimeEncoder
 	encCfg.CallerKey = "caller"
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encCfg),
-		zapcore.AddSync(os.Stdout),
-		zap.NewAtomicLevelAt(level),
-	)
-
-	Log = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
-	SugaredLog = Log.Sugar()
-}
-
-// TimestampedLumberjackWriter wraps lumberjack.Logger and adds timestamp-based file rotation.
-type TimestampedLumberjackWriter struct {
-	*lumberjack.Logger
-	baseFilename string
-}
-
-// NewTimestampedLumberjackWriter creates a new TimestampedLumberjackWriter.
-func NewTimestampedLumberjackWriter(filename string, maxSize, maxBackups, maxAge int, compress bool) *TimestampedLumberjackWriter {
-	return &TimestampedLumberjackWriter{
-		Logger: &lumberjack.Logger{
-			Filename:   filename,
-			MaxSize:    maxSize,
-			MaxBackups: maxBackups,
-			MaxAge:     maxAge,
-			Compress:   compress,
-		},
-		baseFilename: filename,
-	}
-}
-
-// Write writes to the original log file and rotates it with a timestamp when it's closed.
-func (t *TimestampedLumberjackWriter) Write(p []byte) (n int, err error) {
-	fileLock.Lock() // Lock the file access
-	defer fileLock.Unlock()
-
-	n, err = t.Logger.Write(p)
-	if err != nil {
-		return n, err
-	}
-
-	// Create timestamped rotated filename
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	rotatedFilename := fmt.Sprintf("%s.%s.gz", t.baseFilename, timestamp)
-
-	// Attempt to compress and rename the log file with retry mechanism
-	err = t.compressAndRename(rotatedFilename)
-	return n, err
-}
-
-// compressAndRename handles the renaming and compressing of the log file.
-func (t *TimestampedLumberjackWriter) compressAndRename(newFilename string) error {
-	var err error
-	for i := 0; i < 3; i++ {
-		err = t.tryCompressAndRename(newFilename)
-		if err == nil {
-			return nil
+	destinations := strings.Split(os.Getenv("LOG_DESTINATION"), ",")
+
+	// Warnf/Log aren't usable until SugaredLog is built below, so defects in
+	// LOG_DESTINATION are collected here and only logged once that's done.
+	var deferredWarnings []string
+
+	var cores []zapcore.Core
+	for _, dest := range destinations {
+		switch strings.TrimSpace(strings.ToLower(dest)) {
+		case "stdout":
+			cores = append(cores, zapcore.NewCore(
+				zapcore.NewJSONEncoder(encCfg),
+				zapcore.AddSync(os.Stdout),
+				atomicLevel,
+			))
+		case "file":
+			logFile := os.Getenv("LOG_FILE")
+			if logFile == "" {
+				deferredWarnings = append(deferredWarnings, "LOG_DESTINATION includes file but LOG_FILE is empty, skipping")
+				continue
+			}
+			// Plain lumberjack.Logger, rotating by size like audit.go's
+			// auditWriter.
+			fileWriter := &lumberjack.Logger{
+				Filename:   logFile,
+				MaxSize:    envIntOrDefault("LOG_MAX_SIZE_MB", 100),
+				MaxBackups: envIntOrDefault("LOG_MAX_BACKUPS", 3),
+				MaxAge:     envIntOrDefault("LOG_MAX_AGE_DAYS", 28),
+				Compress:   strings.EqualFold(os.Getenv("LOG_COMPRESS"), "true"),
+			}
+			cores = append(cores, zapcore.NewCore(
+				zapcore.NewJSONEncoder(encCfg),
+				zapcore.AddSync(fileWriter),
+				atomicLevel,
+			))
+		case "journald":
+			cores = append(cores, newJournaldCore(atomicLevel))
+		case "":
+			// tolerate a trailing/leading comma in LOG_DESTINATION
+		default:
+			deferredWarnings = append(deferredWarnings, fmt.Sprintf("Unsupported LOG_DESTINATION %q, ignoring", dest))
 		}
-		time.Sleep(time.Second * 1) // Retry after a second
 	}
-	return err
-}
 
-// tryCompressAndRename performs the actual compression and renaming.
-func (t *TimestampedLumberjackWriter) tryCompressAndRename(newFilename string) error {
-	// Open the current log file
-	currentFile, err := os.Open(t.baseFilename)
-	if err != nil {
-		return err
+	// Default to stdout if LOG_DESTINATION was empty or every destination was invalid.
+	if len(cores) == 0 {
+		cores = append(cores, zapcore.NewCore(
+			zapcore.NewJSONEncoder(encCfg),
+			zapcore.AddSync(os.Stdout),
+			atomicLevel,
+		))
 	}
-	defer currentFile.Close()
 
-	// Create a temporary file
-	tmpFile, err := os.Create(newFilename + ".tmp")
-	if err != nil {
-		return err
-	}
-	defer tmpFile.Close()
+	core := zapcore.NewTee(cores...)
 
-	// Rename the current log file to temporary file
-	err = os.Rename(t.baseFilename, tmpFile.Name())
-	if err != nil {
-		return err
-	}
+	Log = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	SugaredLog = Log.Sugar()
 
-	// Compress the temporary file
-	err = compressFile(tmpFile.Name())
-	if err != nil {
-		return err
+	for _, msg := range deferredWarnings {
+		Warnf(msg)
 	}
 
-	// Finally, rename the temporary file to the desired final filename
-	err = os.Rename(tmpFile.Name(), newFilename)
-	return err
+	watchLevelToggle()
+}
+
+// watchLevelToggle lets an operator flip between DEBUG and INFO at runtime
+// via SIGUSR1, without restarting a long-running cron job.
+func watchLevelToggle() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1)
+	go func() {
+		for range sigs {
+			if atomicLevel.Level() == zap.DebugLevel {
+				SetLevel(zap.InfoLevel)
+				Log.Info("SIGUSR1 received, switched log level to INFO")
+			} else {
+				SetLevel(zap.DebugLevel)
+				Log.Info("SIGUSR1 received, switched log level to DEBUG")
+			}
+		}
+	}()
 }
 
-// compressFile compresses the log file to gzip format.
-func compressFile(filename string) error {
-	// Open the renamed log file
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
+// envIntOrDefault reads an int env var, falling back to def if it is unset
+// or not a valid integer.
+func envIntOrDefault(key string, def int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
 	}
-	defer file.Close()
-
-	// Create a gzip file with a .gz extension
-	compressedFile, err := os.Create(filename + ".gz")
+	n, err := strconv.Atoi(val)
 	if err != nil {
-		return err
+		return def
 	}
-	defer compressedFile.Close()
-
-	// Compress the log file
-	gzWriter := gzip.NewWriter(compressedFile)
-	defer gzWriter.Close()
-
-	// Copy the contents of the original file into the gzip file
-	_, err = io.Copy(gzWriter, file)
-	return err
+	return n
 }
 
 // Wrapper functions for Infof, Debugf, Warnf, and Errorf
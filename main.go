@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
 	"os"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -11,7 +13,6 @@ import (
 	"aws-cleaner/services"
 
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/joho/godotenv"
 )
 
@@ -19,6 +20,7 @@ func init() {
 	// Set the log level to DEBUG (or any other level you prefer)
 	os.Setenv("LOG_LEVEL", "DEBUG")
 	logger.InitLogger()
+	logger.InitAuditLog()
 
 	if err := godotenv.Load(".env"); err != nil {
 		logger.Warnf("No .env file found, fallback to system env")
@@ -26,14 +28,25 @@ func init() {
 }
 
 func main() {
+	resume := flag.Bool("resume", false, "resume an interrupted cleanup using the manifest in MANIFEST_DIR")
+	flag.Parse()
+
 	// Load env
 	resourceType := os.Getenv("RESOURCE_TYPE")
 	tagKey := os.Getenv("TAG_KEY")
 	tagValue := os.Getenv("TAG_VALUE")
 	deleteCountStr := os.Getenv("DELETE_COUNT")
 	keepCountStr := os.Getenv("KEEP_COUNT")
+	maxAgeStr := os.Getenv("MAX_AGE")
+	reclaimSizeStr := os.Getenv("RECLAIM_SIZE")
 	awsRegion := os.Getenv("AWS_REGION")
 	sortBy := os.Getenv("SORT_BY") // time|id
+	dryRunStr := os.Getenv("DRY_RUN")
+	preDeleteCmd := os.Getenv("PRE_DELETE_CMD")
+	postDeleteCmd := os.Getenv("POST_DELETE_CMD")
+	hookTimeoutStr := os.Getenv("HOOK_TIMEOUT")
+	manifestDir := os.Getenv("MANIFEST_DIR")
+	manifestResumeWindowStr := os.Getenv("MANIFEST_RESUME_WINDOW")
 
 	logger.Info("Loaded config",
 		zap.String("RESOURCE_TYPE", resourceType),
@@ -41,8 +54,16 @@ func main() {
 		zap.String("TAG_VALUE", tagValue),
 		zap.String("DELETE_COUNT", deleteCountStr),
 		zap.String("KEEP_COUNT", keepCountStr),
+		zap.String("MAX_AGE", maxAgeStr),
+		zap.String("RECLAIM_SIZE", reclaimSizeStr),
 		zap.String("AWS_REGION", awsRegion),
 		zap.String("SORT_BY", sortBy),
+		zap.String("DRY_RUN", dryRunStr),
+		zap.String("PRE_DELETE_CMD", preDeleteCmd),
+		zap.String("POST_DELETE_CMD", postDeleteCmd),
+		zap.String("MANIFEST_DIR", manifestDir),
+		zap.String("MANIFEST_RESUME_WINDOW", manifestResumeWindowStr),
+		zap.Bool("resume", *resume),
 	)
 
 	// Validate variables
@@ -97,10 +118,65 @@ func main() {
 
 	logger.Debug(deleteCountStr)
 
+	var maxAge *services.Duration
+	if maxAgeStr != "" {
+		val, err := services.ParseDuration(maxAgeStr)
+		if err != nil {
+			logger.Errorf("Invalid MAX_AGE: %v", err)
+			invalidVar = true
+		} else {
+			maxAge = val
+		}
+	}
+
+	var reclaimSize *int64
+	if reclaimSizeStr != "" {
+		val, err := services.ParseSize(reclaimSizeStr)
+		if err != nil {
+			logger.Errorf("Invalid RECLAIM_SIZE: %v", err)
+			invalidVar = true
+		} else {
+			reclaimSize = &val
+		}
+	}
+
 	if sortBy == "" {
 		sortBy = "created_time"
 	}
 
+	dryRun := false
+	if dryRunStr != "" {
+		val, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			logger.Errorf("Invalid DRY_RUN: %v", err)
+			invalidVar = true
+		} else {
+			dryRun = val
+		}
+	}
+
+	hookTimeout := 30 * time.Second
+	if hookTimeoutStr != "" {
+		val, err := services.ParseDuration(hookTimeoutStr)
+		if err != nil {
+			logger.Errorf("Invalid HOOK_TIMEOUT: %v", err)
+			invalidVar = true
+		} else {
+			hookTimeout = val.Duration
+		}
+	}
+
+	manifestResumeWindow := 24 * time.Hour
+	if manifestResumeWindowStr != "" {
+		val, err := services.ParseDuration(manifestResumeWindowStr)
+		if err != nil {
+			logger.Errorf("Invalid MANIFEST_RESUME_WINDOW: %v", err)
+			invalidVar = true
+		} else {
+			manifestResumeWindow = val.Duration
+		}
+	}
+
 	if invalidVar {
 		return
 	}
@@ -111,14 +187,51 @@ func main() {
 		logger.Errorf("Unable to load SDK config: %v", err)
 		return
 	}
-	ec2Client := ec2.NewFromConfig(cfg)
-
-	switch resourceType {
-	case "ebs-snapshot":
-		logger.Debug("CleanupSnapshots")
-		services.CleanupSnapshots(ec2Client, tagKey, tagValue, deleteCount, keepCount, sortBy)
-	default:
+	resource, ok := services.Lookup(resourceType, cfg)
+	if !ok {
 		logger.Errorf("Unsupported resource type: %s", resourceType)
 		return
 	}
+
+	opts := services.RetentionOptions{
+		MaxAge:      maxAge,
+		ReclaimSize: reclaimSize,
+		DeleteCount: deleteCount,
+		KeepCount:   keepCount,
+	}
+
+	run := services.RunOptions{
+		DryRun:        dryRun,
+		PreDeleteCmd:  preDeleteCmd,
+		PostDeleteCmd: postDeleteCmd,
+		HookTimeout:   hookTimeout,
+		ManifestDir:   manifestDir,
+		Region:        awsRegion,
+	}
+
+	if *resume {
+		if manifestDir == "" {
+			logger.Errorf("--resume requires MANIFEST_DIR to be set")
+			return
+		}
+		manifest, ok, err := services.LoadResumableManifest(manifestDir, resourceType, manifestResumeWindow)
+		if err != nil {
+			logger.Errorf("Failed to load manifest: %v", err)
+			return
+		}
+		if !ok {
+			logger.Info("Nothing to resume")
+			return
+		}
+		logger.Debug("ResumeCleanup")
+		if err := services.ResumeCleanup(context.TODO(), resource, manifest, run); err != nil {
+			logger.Errorf("ResumeCleanup failed: %v", err)
+		}
+		return
+	}
+
+	logger.Debug("Cleanup")
+	if err := services.Cleanup(context.TODO(), resource, resourceType, tagKey, tagValue, opts, sortBy, run); err != nil {
+		logger.Errorf("Cleanup failed: %v", err)
+	}
 }
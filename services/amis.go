@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"aws-cleaner/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func init() {
+	Register("ami", func(cfg aws.Config) Resource {
+		return &amiResource{client: ec2.NewFromConfig(cfg)}
+	})
+}
+
+// amiResource deregisters AMI candidates and cascades the delete to their
+// backing EBS snapshots, since a deregistered AMI leaves those orphaned.
+type amiResource struct {
+	client *ec2.Client
+}
+
+func (r *amiResource) List(ctx context.Context, filter TagFilter) ([]Item, error) {
+	out, err := r.client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners: []string{"self"},
+		Filters: []types.Filter{
+			{Name: aws.String("tag:" + filter.Key), Values: []string{filter.Value}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(out.Images))
+	for _, img := range out.Images {
+		items = append(items, img)
+	}
+	return items, nil
+}
+
+func (r *amiResource) SortKey(item Item) (time.Time, string) {
+	img := item.(types.Image)
+	ts, _ := time.Parse(time.RFC3339, aws.ToString(img.CreationDate))
+	return ts, aws.ToString(img.ImageId)
+}
+
+func (r *amiResource) SizeBytes(item Item) int64 {
+	img := item.(types.Image)
+	var total int64
+	for _, bdm := range img.BlockDeviceMappings {
+		if bdm.Ebs != nil {
+			total += gibToBytes(aws.ToInt32(bdm.Ebs.VolumeSize))
+		}
+	}
+	return total
+}
+
+func (r *amiResource) Describe(ctx context.Context, id string) (Item, bool, error) {
+	out, err := r.client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds: []string{id},
+	})
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if len(out.Images) == 0 {
+		return nil, false, nil
+	}
+	return out.Images[0], true, nil
+}
+
+func (r *amiResource) Delete(ctx context.Context, item Item) error {
+	img := item.(types.Image)
+
+	dctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, err := r.client.DeregisterImage(dctx, &ec2.DeregisterImageInput{
+		ImageId: img.ImageId,
+	})
+	if err != nil {
+		logger.Errorf("Failed to deregister AMI %v: %v", aws.ToString(img.ImageId), err)
+		return err
+	}
+	logger.Infof("Deregistered AMI %s", aws.ToString(img.ImageId))
+
+	// Cascade: a deregistered AMI leaves its backing snapshots orphaned.
+	for _, bdm := range img.BlockDeviceMappings {
+		if bdm.Ebs == nil || bdm.Ebs.SnapshotId == nil {
+			continue
+		}
+
+		sctx, scancel := context.WithTimeout(ctx, 10*time.Second)
+		_, err := r.client.DeleteSnapshot(sctx, &ec2.DeleteSnapshotInput{
+			SnapshotId: bdm.Ebs.SnapshotId,
+		})
+		scancel()
+		if err != nil {
+			logger.Errorf("Failed to delete backing snapshot %v for AMI %v: %v",
+				aws.ToString(bdm.Ebs.SnapshotId), aws.ToString(img.ImageId), err)
+			continue
+		}
+		logger.Infof("Deleted backing snapshot %s for AMI %s", aws.ToString(bdm.Ebs.SnapshotId), aws.ToString(img.ImageId))
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aws-cleaner/logger"
+)
+
+func printItemsList(items []Item, resource Resource, message string) {
+	for _, item := range items {
+		ts, id := resource.SortKey(item)
+		tsStr := "-"
+		if !ts.IsZero() {
+			tsStr = ts.Format("2006-01-02 15:04:05")
+		}
+		logger.Infof("%s ID=%s, Time=%s", message, id, tsStr)
+	}
+}
+
+func sortByCreatedTime(items []Item, resource Resource, order string) []Item {
+	sort.Slice(items, func(i, j int) bool {
+		ti, _ := resource.SortKey(items[i])
+		tj, _ := resource.SortKey(items[j])
+		if order == "desc" {
+			if ti.IsZero() {
+				return false
+			}
+			if tj.IsZero() {
+				return true
+			}
+			return ti.After(tj)
+		}
+		// default asc
+		if ti.IsZero() {
+			return false
+		}
+		if tj.IsZero() {
+			return true
+		}
+		return ti.Before(tj)
+	})
+	return items
+}
+
+// Cleanup drives the shared list -> sort -> select -> delete flow against
+// any registered Resource, so RESOURCE_TYPE can be "ebs-snapshot",
+// "ebs-volume", "ami" or anything else Register has been called for.
+// resourceType is the RESOURCE_TYPE name the resource was looked up under;
+// it is only used to label the audit trail and hook environment.
+func Cleanup(ctx context.Context, resource Resource, resourceType, tagKey, tagValue string, retention RetentionOptions, sortBy string, run RunOptions) error {
+	items, err := resource.List(ctx, TagFilter{Key: tagKey, Value: tagValue})
+	if err != nil {
+		return fmt.Errorf("listing resources: %w", err)
+	}
+
+	if len(items) == 0 {
+		logger.Info("No resources found for given tag filter")
+		return nil
+	}
+
+	switch sortBy {
+	case "created_time_asc":
+		items = sortByCreatedTime(items, resource, "asc")
+	case "created_time_desc":
+		items = sortByCreatedTime(items, resource, "desc")
+		printItemsList(items, resource, "Sort by created time desc: ")
+	default:
+		return fmt.Errorf("unsupported SORT_BY: %s", sortBy)
+	}
+
+	toDelete, err := selectToDelete(items, resource, retention)
+	if err != nil {
+		return fmt.Errorf("selecting resources to delete: %w", err)
+	}
+	printItemsList(toDelete, resource, "Delete: ")
+
+	// Persist the exact set we're about to delete before issuing a single
+	// delete call, so a crash mid-run leaves a record of what was partially
+	// removed (see ResumeCleanup). Skipped under DRY_RUN: nothing was
+	// actually deleted, so a manifest here would look resumable and a later
+	// --resume with DRY_RUN unset would really delete what was only planned.
+	var manifest *Manifest
+	if run.ManifestDir != "" && !run.DryRun {
+		manifest = NewManifest(run.ManifestDir, run.Region, resourceType, TagFilter{Key: tagKey, Value: tagValue}, toDelete, resource)
+		if err := manifest.Save(); err != nil {
+			return fmt.Errorf("saving manifest: %w", err)
+		}
+	}
+
+	ids := make([]string, 0, len(toDelete))
+	var totalBytes int64
+	for _, item := range toDelete {
+		_, id := resource.SortKey(item)
+		ids = append(ids, id)
+		totalBytes += resource.SizeBytes(item)
+	}
+
+	baseHookEnv := map[string]string{
+		"AWS_CLEANER_COUNT":       strconv.Itoa(len(toDelete)),
+		"AWS_CLEANER_IDS":         strings.Join(ids, ","),
+		"AWS_CLEANER_TOTAL_BYTES": strconv.FormatInt(totalBytes, 10),
+	}
+	if err := runHookCommand(ctx, run.PreDeleteCmd, baseHookEnv, run.HookTimeout); err != nil {
+		logger.Errorf("PRE_DELETE_CMD failed: %v", err)
+	}
+
+	tags := map[string]string{tagKey: tagValue}
+	var succeeded, failed int
+	for _, item := range toDelete {
+		createdAt, id := resource.SortKey(item)
+		sizeBytes := resource.SizeBytes(item)
+
+		if run.DryRun {
+			logger.Infof("DRY_RUN: would delete %s %s", resourceType, id)
+		} else {
+			if err := resource.Delete(ctx, item); err != nil {
+				logger.Errorf("Failed to delete resource: %v", err)
+				failed++
+				continue
+			}
+			if manifest != nil {
+				if err := manifest.MarkDone(id); err != nil {
+					logger.Errorf("Failed to update manifest: %v", err)
+				}
+			}
+		}
+		succeeded++
+
+		logger.Audit(logger.AuditEvent{
+			Timestamp:    time.Now(),
+			Action:       "delete",
+			ResourceType: resourceType,
+			ResourceID:   id,
+			Tags:         tags,
+			SizeBytes:    sizeBytes,
+			CreatedAt:    createdAt,
+			DryRun:       run.DryRun,
+		})
+	}
+
+	postHookEnv := map[string]string{
+		"AWS_CLEANER_COUNT":       baseHookEnv["AWS_CLEANER_COUNT"],
+		"AWS_CLEANER_IDS":         baseHookEnv["AWS_CLEANER_IDS"],
+		"AWS_CLEANER_TOTAL_BYTES": baseHookEnv["AWS_CLEANER_TOTAL_BYTES"],
+		"AWS_CLEANER_SUCCEEDED":   strconv.Itoa(succeeded),
+		"AWS_CLEANER_FAILED":      strconv.Itoa(failed),
+	}
+	if err := runHookCommand(ctx, run.PostDeleteCmd, postHookEnv, run.HookTimeout); err != nil {
+		logger.Errorf("POST_DELETE_CMD failed: %v", err)
+	}
+
+	return nil
+}
+
+// ResumeCleanup continues an interrupted Cleanup run from a manifest: some
+// manifested ids may already be gone by now, so each pending entry is
+// re-described before being deleted, and marked done as soon as AWS
+// confirms (or as soon as a re-describe finds it already gone).
+func ResumeCleanup(ctx context.Context, resource Resource, manifest *Manifest, run RunOptions) error {
+	tags := map[string]string{manifest.TagFilter.Key: manifest.TagFilter.Value}
+
+	for _, entry := range manifest.pending() {
+		item, found, err := resource.Describe(ctx, entry.ResourceID)
+		if err != nil {
+			logger.Errorf("Failed to re-describe %s: %v", entry.ResourceID, err)
+			continue
+		}
+		if !found {
+			logger.Infof("%s is already gone, marking done", entry.ResourceID)
+			if err := manifest.MarkDone(entry.ResourceID); err != nil {
+				logger.Errorf("Failed to update manifest: %v", err)
+			}
+			continue
+		}
+
+		if run.DryRun {
+			logger.Infof("DRY_RUN: would delete %s %s", manifest.ResourceType, entry.ResourceID)
+		} else {
+			if err := resource.Delete(ctx, item); err != nil {
+				logger.Errorf("Failed to delete %s: %v", entry.ResourceID, err)
+				continue
+			}
+			if err := manifest.MarkDone(entry.ResourceID); err != nil {
+				logger.Errorf("Failed to update manifest: %v", err)
+			}
+		}
+
+		logger.Audit(logger.AuditEvent{
+			Timestamp:    time.Now(),
+			Action:       "delete",
+			ResourceType: manifest.ResourceType,
+			ResourceID:   entry.ResourceID,
+			Tags:         tags,
+			SizeBytes:    entry.SizeBytes,
+			CreatedAt:    entry.CreatedAt,
+			DryRun:       run.DryRun,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"aws-cleaner/logger"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RunOptions controls how Cleanup actually performs (or doesn't perform)
+// deletions: DRY_RUN, the PRE_DELETE_CMD/POST_DELETE_CMD hooks that run
+// once around the deletion loop, and where to persist the pre-deletion
+// manifest used by ResumeCleanup.
+type RunOptions struct {
+	DryRun        bool
+	PreDeleteCmd  string
+	PostDeleteCmd string
+	HookTimeout   time.Duration
+	ManifestDir   string
+	Region        string
+}
+
+// runHookCommand runs a PRE_DELETE_CMD/POST_DELETE_CMD shell command with
+// extra environment variables, forwarding its stdout/stderr through the
+// structured logger. Inspired by docker-volume-backup's runLabeledCommands.
+// It is a no-op if cmd is empty.
+func runHookCommand(ctx context.Context, cmd string, env map[string]string, timeout time.Duration) error {
+	if cmd == "" {
+		return nil
+	}
+
+	hctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c := exec.CommandContext(hctx, "sh", "-c", cmd)
+	c.Env = os.Environ()
+	for k, v := range env {
+		c.Env = append(c.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	writer := logger.NewZapWriter(logger.Log, zapcore.InfoLevel)
+	c.Stdout = writer
+	c.Stderr = writer
+
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running hook command %q: %w", cmd, err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestEntry records one resource Cleanup is about to delete.
+type ManifestEntry struct {
+	ResourceID string    `json:"resource_id"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	SizeBytes  int64     `json:"size_bytes"`
+	Done       bool      `json:"done"`
+}
+
+// Manifest is written to MANIFEST_DIR before a deletion loop starts, and
+// rewritten as entries are confirmed deleted. Modeled on etcd's rule of
+// "save the snapshot file before saving any other entries": a crash mid-run
+// always leaves a record of what was partially removed.
+type Manifest struct {
+	Timestamp    time.Time       `json:"timestamp"`
+	Region       string          `json:"region"`
+	ResourceType string          `json:"resource_type"`
+	TagFilter    TagFilter       `json:"tag_filter"`
+	Entries      []ManifestEntry `json:"entries"`
+
+	path string
+}
+
+// NewManifest builds a Manifest for the given selection, ready to Save.
+func NewManifest(dir, region, resourceType string, filter TagFilter, items []Item, resource Resource) *Manifest {
+	entries := make([]ManifestEntry, 0, len(items))
+	for _, item := range items {
+		createdAt, id := resource.SortKey(item)
+		entries = append(entries, ManifestEntry{
+			ResourceID: id,
+			CreatedAt:  createdAt,
+			SizeBytes:  resource.SizeBytes(item),
+		})
+	}
+	return &Manifest{
+		Timestamp:    time.Now(),
+		Region:       region,
+		ResourceType: resourceType,
+		TagFilter:    filter,
+		Entries:      entries,
+		path:         manifestPath(dir, resourceType),
+	}
+}
+
+func manifestPath(dir, resourceType string) string {
+	return filepath.Join(dir, fmt.Sprintf("manifest-%s.json", resourceType))
+}
+
+// Save writes the manifest to disk, overwriting any previous manifest for
+// the same resource type at this path.
+func (m *Manifest) Save() error {
+	if m.path == "" {
+		return fmt.Errorf("manifest: no path set")
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return fmt.Errorf("manifest: creating directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("manifest: marshal: %w", err)
+	}
+	return os.WriteFile(m.path, data, 0o644)
+}
+
+// MarkDone flags resourceID as deleted and persists the manifest.
+func (m *Manifest) MarkDone(resourceID string) error {
+	for i := range m.Entries {
+		if m.Entries[i].ResourceID == resourceID {
+			m.Entries[i].Done = true
+			break
+		}
+	}
+	return m.Save()
+}
+
+// pending returns the entries not yet marked done.
+func (m *Manifest) pending() []ManifestEntry {
+	var entries []ManifestEntry
+	for _, e := range m.Entries {
+		if !e.Done {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// done reports whether every entry has been marked done.
+func (m *Manifest) done() bool {
+	return len(m.pending()) == 0
+}
+
+// LoadResumableManifest looks in dir for an unfinished manifest for
+// resourceType, newer than window, and loads it. It reports (nil, false, nil)
+// if there is nothing to resume.
+func LoadResumableManifest(dir, resourceType string, window time.Duration) (*Manifest, bool, error) {
+	path := manifestPath(dir, resourceType)
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("manifest: stat: %w", err)
+	}
+	if time.Since(info.ModTime()) > window {
+		return nil, false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("manifest: read: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, false, fmt.Errorf("manifest: unmarshal: %w", err)
+	}
+	m.path = path
+
+	if m.done() {
+		return nil, false, nil
+	}
+	return &m, true, nil
+}
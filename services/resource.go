@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+)
+
+// TagFilter selects AWS resources carrying tag Key=Value.
+type TagFilter struct {
+	Key   string
+	Value string
+}
+
+// Item is an opaque handle to a single resource instance as returned by a
+// Resource's List call. Each Resource implementation hands out and consumes
+// its own concrete type (e.g. types.Snapshot), so callers outside that
+// Resource should treat Item as opaque.
+type Item interface{}
+
+// Resource is the extension point every cleanable AWS resource type
+// implements. Register one under a name and RESOURCE_TYPE can drive it
+// through the shared Cleanup flow.
+type Resource interface {
+	// List returns every item matching the tag filter, unsorted.
+	List(ctx context.Context, filter TagFilter) ([]Item, error)
+	// SortKey returns the timestamp and a tiebreaker id used to order items
+	// for retention decisions (oldest first).
+	SortKey(item Item) (time.Time, string)
+	// SizeBytes returns how many bytes deleting this item would reclaim.
+	SizeBytes(item Item) int64
+	// Delete removes a single item.
+	Delete(ctx context.Context, item Item) error
+	// Describe re-fetches a single item by the id SortKey reported for it.
+	// It is used to resume an interrupted cleanup: some manifested ids may
+	// already be gone (found is false), and the ones that remain need their
+	// full details to delete correctly.
+	Describe(ctx context.Context, id string) (item Item, found bool, err error)
+}
+
+// Factory builds a Resource bound to a given AWS config.
+type Factory func(cfg aws.Config) Resource
+
+var registry = map[string]Factory{}
+
+// Register makes a Resource factory available under name for RESOURCE_TYPE to select.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// Lookup builds the Resource registered under name, or reports it isn't registered.
+func Lookup(name string, cfg aws.Config) (Resource, bool) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, false
+	}
+	return factory(cfg), true
+}
+
+// isNotFoundError reports whether err is an AWS API error for a resource
+// that no longer exists, as returned by a DescribeX call filtered by id.
+func isNotFoundError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return strings.Contains(apiErr.ErrorCode(), "NotFound")
+	}
+	return false
+}
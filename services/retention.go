@@ -0,0 +1,241 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration wraps time.Duration so retention windows can be parsed from env
+// vars today and, later, from a YAML/TOML config via encoding.TextUnmarshaler.
+// Besides Go's native duration syntax ("720h30m"), it also accepts a "d"
+// (day) suffix such as "30d", which time.ParseDuration does not support.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		return fmt.Errorf("duration: empty value")
+	}
+
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return fmt.Errorf("duration: invalid day value %q: %w", s, err)
+		}
+		d.Duration = time.Duration(days * 24 * float64(time.Hour))
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("duration: %w", err)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// ParseDuration parses a duration string (e.g. "720h", "30d") into a Duration.
+func ParseDuration(s string) (*Duration, error) {
+	d := &Duration{}
+	if err := d.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ParseSize parses a human-readable byte size such as "500GB" or "10GiB"
+// into a number of bytes. Binary (GiB/MiB/KiB) and decimal (GB/MB/KB)
+// suffixes are both supported; a bare number is treated as bytes.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("size: empty value")
+	}
+
+	units := []struct {
+		suffix     string
+		multiplier float64
+	}{
+		{"TiB", 1 << 40},
+		{"GiB", 1 << 30},
+		{"MiB", 1 << 20},
+		{"KiB", 1 << 10},
+		{"TB", 1e12},
+		{"GB", 1e9},
+		{"MB", 1e6},
+		{"KB", 1e3},
+		{"B", 1},
+	}
+
+	upper := strings.ToUpper(s)
+	for _, u := range units {
+		su := strings.ToUpper(u.suffix)
+		if !strings.HasSuffix(upper, su) {
+			continue
+		}
+		numPart := strings.TrimSpace(s[:len(s)-len(u.suffix)])
+		val, err := strconv.ParseFloat(numPart, 64)
+		if err != nil {
+			return 0, fmt.Errorf("size: invalid value %q: %w", s, err)
+		}
+		return int64(val * u.multiplier), nil
+	}
+
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("size: invalid value %q: %w", s, err)
+	}
+	return int64(val), nil
+}
+
+// gibToBytes converts an EBS VolumeSize (reported in GiB) to bytes.
+func gibToBytes(gib int32) int64 {
+	return int64(gib) * (1 << 30)
+}
+
+// RetentionOptions bundles every knob selectToDelete can select on. MaxAge
+// and ReclaimSize build up the set of candidates to delete; DeleteCount and
+// KeepCount then cap that set from above/below.
+type RetentionOptions struct {
+	MaxAge      *Duration // delete anything older than now - MaxAge
+	ReclaimSize *int64    // keep deleting (oldest first) until this many bytes are freed
+	DeleteCount *int
+	KeepCount   *int
+}
+
+// selectToDelete runs the retention strategy pipeline over items, which
+// Cleanup has already sorted per SORT_BY: MAX_AGE and RECLAIM_SIZE decide
+// which items are candidates for deletion (RECLAIM_SIZE always reclaims
+// oldest-first, independent of SORT_BY), then DELETE_COUNT/KEEP_COUNT cap
+// that candidate set as an upper/lower bound over the SORT_BY order.
+func selectToDelete(items []Item, resource Resource, opts RetentionOptions) ([]Item, error) {
+	n := len(items)
+	if n == 0 {
+		return []Item{}, nil
+	}
+
+	if opts.DeleteCount != nil && opts.KeepCount != nil {
+		return nil, fmt.Errorf("both deleteCount (%d) and keepCount (%d) are set, only one should be used",
+			*opts.DeleteCount, *opts.KeepCount)
+	}
+
+	usingStrategy := opts.MaxAge != nil || opts.ReclaimSize != nil
+	marked := make([]bool, n)
+
+	// MAX_AGE: mark every item older than now - MaxAge.
+	if opts.MaxAge != nil {
+		cutoff := time.Now().Add(-opts.MaxAge.Duration)
+		for i, item := range items {
+			ts, _ := resource.SortKey(item)
+			if !ts.IsZero() && ts.Before(cutoff) {
+				marked[i] = true
+			}
+		}
+	}
+
+	// RECLAIM_SIZE: on top of whatever MAX_AGE already marked, keep popping
+	// oldest-first and summing SizeBytes until the freed total meets the
+	// target. items arrives sorted per SORT_BY, which Cleanup may set to
+	// created_time_desc for display, so reclaim walks its own oldest-first
+	// ordering rather than assuming the input order.
+	if opts.ReclaimSize != nil {
+		ascOrder := make([]int, n)
+		for i := range ascOrder {
+			ascOrder[i] = i
+		}
+		sort.Slice(ascOrder, func(a, b int) bool {
+			ta, _ := resource.SortKey(items[ascOrder[a]])
+			tb, _ := resource.SortKey(items[ascOrder[b]])
+			if ta.IsZero() {
+				return false
+			}
+			if tb.IsZero() {
+				return true
+			}
+			return ta.Before(tb)
+		})
+
+		var freed int64
+		for _, i := range ascOrder {
+			if marked[i] {
+				freed += resource.SizeBytes(items[i])
+			}
+		}
+		for _, i := range ascOrder {
+			if freed >= *opts.ReclaimSize {
+				break
+			}
+			if marked[i] {
+				continue
+			}
+			marked[i] = true
+			freed += resource.SizeBytes(items[i])
+		}
+	}
+
+	var result []Item
+	if usingStrategy {
+		for i, item := range items {
+			if marked[i] {
+				result = append(result, item)
+			}
+		}
+	} else {
+		result = items
+	}
+
+	// KEEP_COUNT is a lower bound: it must never let MAX_AGE/RECLAIM_SIZE
+	// select more than n - KeepCount items.
+	if opts.KeepCount != nil {
+		if *opts.KeepCount < 0 {
+			return nil, fmt.Errorf("keep count must be greater than or equal to 0")
+		}
+		maxDeletable := n - *opts.KeepCount
+		if maxDeletable < 0 {
+			maxDeletable = 0
+		}
+		switch {
+		case usingStrategy:
+			if len(result) > maxDeletable {
+				return nil, fmt.Errorf(
+					"KEEP_COUNT=%d forbids deleting more than %d item(s), but MAX_AGE/RECLAIM_SIZE selected %d",
+					*opts.KeepCount, maxDeletable, len(result))
+			}
+		case *opts.KeepCount == 0:
+			result = items
+		case *opts.KeepCount >= n:
+			result = []Item{}
+		default:
+			result = items[*opts.KeepCount:] // delete everything after the first N
+		}
+	}
+
+	// DELETE_COUNT is an upper bound on the final selection.
+	if opts.DeleteCount != nil {
+		if *opts.DeleteCount < 0 {
+			return []Item{}, nil
+		}
+		switch {
+		case usingStrategy:
+			if len(result) > *opts.DeleteCount {
+				result = result[:*opts.DeleteCount]
+			}
+		case *opts.DeleteCount >= n:
+			result = items
+		default:
+			result = items[:*opts.DeleteCount] // delete the first N
+		}
+	}
+
+	if !usingStrategy && opts.DeleteCount == nil && opts.KeepCount == nil {
+		return []Item{}, nil
+	}
+
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"aws-cleaner/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func init() {
+	Register("ebs-volume", func(cfg aws.Config) Resource {
+		return &volumeResource{client: ec2.NewFromConfig(cfg)}
+	})
+}
+
+// volumeResource cleans up unattached ("available") EBS volumes.
+type volumeResource struct {
+	client *ec2.Client
+}
+
+func (r *volumeResource) List(ctx context.Context, filter TagFilter) ([]Item, error) {
+	out, err := r.client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("tag:" + filter.Key), Values: []string{filter.Value}},
+			{Name: aws.String("status"), Values: []string{string(types.VolumeStateAvailable)}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(out.Volumes))
+	for _, vol := range out.Volumes {
+		items = append(items, vol)
+	}
+	return items, nil
+}
+
+func (r *volumeResource) SortKey(item Item) (time.Time, string) {
+	vol := item.(types.Volume)
+	var ts time.Time
+	if vol.CreateTime != nil {
+		ts = *vol.CreateTime
+	}
+	return ts, aws.ToString(vol.VolumeId)
+}
+
+func (r *volumeResource) SizeBytes(item Item) int64 {
+	vol := item.(types.Volume)
+	return gibToBytes(aws.ToInt32(vol.Size))
+}
+
+func (r *volumeResource) Describe(ctx context.Context, id string) (Item, bool, error) {
+	out, err := r.client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{id},
+	})
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if len(out.Volumes) == 0 {
+		return nil, false, nil
+	}
+	return out.Volumes[0], true, nil
+}
+
+func (r *volumeResource) Delete(ctx context.Context, item Item) error {
+	vol := item.(types.Volume)
+
+	dctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, err := r.client.DeleteVolume(dctx, &ec2.DeleteVolumeInput{
+		VolumeId: vol.VolumeId,
+	})
+	if err != nil {
+		logger.Errorf("Failed to delete volume %v: %v", aws.ToString(vol.VolumeId), err)
+		return err
+	}
+	logger.Infof("Deleted volume %s", aws.ToString(vol.VolumeId))
+	return nil
+}